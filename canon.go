@@ -0,0 +1,94 @@
+package main
+
+import (
+	"math/big"
+	"sync"
+)
+
+// symmetries returns the hole-index permutation for each of the six
+// symmetries of a triangle of side n: the identity, two rotations
+// (120 and 240 degrees), and three reflections. perms[0] is always
+// the identity.
+//
+// Each hole is addressed by simplex coordinates (a, b, c) with
+// a + b + c = n-1 and a, b, c >= 0; the six symmetries of a triangle
+// are exactly the six permutations of (a, b, c).
+func symmetries(n int) [6][]int {
+	apply := [6]func(a, b, c int) (int, int, int){
+		func(a, b, c int) (int, int, int) { return a, b, c }, // identity
+		func(a, b, c int) (int, int, int) { return c, a, b }, // rotate 120
+		func(a, b, c int) (int, int, int) { return b, c, a }, // rotate 240
+		func(a, b, c int) (int, int, int) { return a, c, b }, // reflect
+		func(a, b, c int) (int, int, int) { return c, b, a }, // reflect
+		func(a, b, c int) (int, int, int) { return b, a, c }, // reflect
+	}
+
+	var perms [6][]int
+	for k, f := range apply {
+		perm := make([]int, holes(n))
+		for row := 0; row < n; row++ {
+			for col := 0; col <= row; col++ {
+				a, b, c := col, row-col, n-1-row
+				a2, b2, _ := f(a, b, c)
+				row2, col2 := a2+b2, a2
+				perm[hole(row, col)] = hole(row2, col2)
+			}
+		}
+		perms[k] = perm
+	}
+	return perms
+}
+
+// Canonicalize returns the numerically smallest board reachable from
+// b by applying one of the triangle's symmetries in perms, i.e. its
+// canonical representative under the D3 symmetry group.
+func Canonicalize(b Board, perms [6][]int) Board {
+	min := b.bits
+	for _, perm := range perms {
+		pb := permuted(b, perm)
+		if pb.bits.Cmp(min) < 0 {
+			min = pb.bits
+		}
+	}
+	return Board{min}
+}
+
+// permuted returns the board that results from relabeling b's holes
+// according to perm, where perm[old] is the new index of hole old.
+func permuted(b Board, perm []int) Board {
+	nb := new(big.Int)
+	for old, new_ := range perm {
+		if b.bits.Bit(old) == 1 {
+			nb.SetBit(nb, new_, 1)
+		}
+	}
+	return Board{nb}
+}
+
+// deadEnds records the canonical form of boards already proven to
+// have no solutions beneath them, so the search can skip any branch
+// whose canonical form it has already ruled out. It's guarded by a
+// mutex so the parallel search in Solve can share one across workers.
+type deadEnds struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newDeadEnds() *deadEnds {
+	return &deadEnds{seen: make(map[string]struct{})}
+}
+
+// Contains reports whether b's canonical form is already known dead.
+func (d *deadEnds) Contains(b Board) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.seen[b.bits.String()]
+	return ok
+}
+
+// Add records b's canonical form as dead.
+func (d *deadEnds) Add(b Board) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[b.bits.String()] = struct{}{}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+// solve runs the depth-first search to completion and returns the
+// number of solutions found, optionally deduplicating symmetric dead
+// branches via the canonical-form table.
+func solve(n int, b Board, dedup bool) int {
+	moves := Moves(n)
+	var perms [6][]int
+	var dead *deadEnds
+	if dedup {
+		perms = symmetries(n)
+		dead = newDeadEnds()
+	}
+	solvedCh := make(chan []Board)
+	go play(b, n, moves, []Board{b}, solvedCh, perms, dead)
+
+	cnt := 0
+	for range solvedCh {
+		cnt++
+	}
+	return cnt
+}
+
+// TestCanonicalizeDedupPreservesSolutionCount asserts that skipping
+// branches via the symmetry-canonical dead-end table doesn't change
+// how many solutions the search finds.
+func TestCanonicalizeDedupPreservesSolutionCount(t *testing.T) {
+	n := 4
+	b := NewGame(n, 0)
+
+	want := solve(n, b, false)
+	got := solve(n, b, true)
+	if got != want {
+		t.Fatalf("dedup changed solution count: got %d, want %d", got, want)
+	}
+}
+
+func BenchmarkPlayWithoutDedup(b *testing.B) {
+	n := 5
+	for i := 0; i < b.N; i++ {
+		solve(n, NewGame(n, 0), false)
+	}
+}
+
+func BenchmarkPlayWithDedup(b *testing.B) {
+	n := 5
+	for i := 0; i < b.N; i++ {
+		solve(n, NewGame(n, 0), true)
+	}
+}
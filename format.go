@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Formatter renders one solved game's path, a sequence of boards from
+// the initial position to a one-peg end state, to w.
+type Formatter interface {
+	Format(w io.Writer, n int, path []Board) error
+}
+
+// ASCIIFormatter renders each board in a path as ASCII art, one after
+// another, matching the original plain-text output.
+type ASCIIFormatter struct{}
+
+func (ASCIIFormatter) Format(w io.Writer, n int, path []Board) error {
+	for _, b := range path {
+		b.Fprint(w, n)
+		fmt.Fprintln(w, " ")
+	}
+	return nil
+}
+
+// JSONFormatter renders a solved game's path as a single Solution
+// object: the board side, the starting empty hole, the ordered
+// {from, over, to} moves recovered from path, and the final board.
+// Indent pretty-prints the object for human-readable "json" output;
+// leave it false for compact, one-line-per-solution "ndjson" streams
+// meant for other tools to consume.
+type JSONFormatter struct {
+	Indent bool
+}
+
+func (f JSONFormatter) Format(w io.Writer, n int, path []Board) error {
+	s, err := newSolution(n, path, Moves(n))
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	if f.Indent {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(s)
+}
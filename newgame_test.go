@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/dgnorton/peggame/rng"
+)
+
+// TestNewGameIsReproducibleUnderSeed pins a fixed seed's starting
+// hole and the final board of the first solution the single-threaded
+// play finds from it, so -seed really does fix the starting board -
+// and the regression tests built on it stay reproducible.
+func TestNewGameIsReproducibleUnderSeed(t *testing.T) {
+	n := 5
+	const seed = 7
+	const wantEmpty = 9
+	const wantFinalHex = "1000"
+
+	b := newGame(n, rng.NewRNGFromSeed(seed))
+
+	empty := -1
+	for h := 0; h < holes(n); h++ {
+		if b.bitValue(h) == 0 {
+			empty = h
+			break
+		}
+	}
+	if empty != wantEmpty {
+		t.Fatalf("seed %d: got empty hole %d, want %d", seed, empty, wantEmpty)
+	}
+
+	moves := Moves(n)
+	solvedCh := make(chan []Board)
+	go play(b, n, moves, []Board{b}, solvedCh, [6][]int{}, nil)
+
+	first, ok := <-solvedCh
+	if !ok {
+		t.Fatalf("seed %d: expected at least one solution", seed)
+	}
+	if got := first[len(first)-1].bits.Text(16); got != wantFinalHex {
+		t.Fatalf("seed %d: first solution's final board is %s, want %s", seed, got, wantFinalHex)
+	}
+}
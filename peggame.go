@@ -8,170 +8,279 @@
 //  1 1 1 1     6   7   8   9
 // 1 1 1 1 1  10  11  12  13  14
 //
-// We use a single unsigned integer to represent the state of a game.
-// The value of the LSB represents hole 0 and the value of bit
-// (1 << 14) represents hole 14.  Simple, small memory footprint,
-// and runs reasonably fast.
+// The board above is the n=5 case.  In general a triangle of side n
+// has n(n+1)/2 holes, numbered row by row starting at the top.  We
+// use a single *big.Int to represent the state of a game so boards
+// much larger than the classic 15-hole triangle still fit.
 package main
 
 import (
+    "context"
     "flag"
 	"fmt"
-	"math/rand"
-	"time"
+	"io"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/dgnorton/peggame/rng"
 )
 
-// possible move
+// move represents a possible jump: P2 is the peg in the middle that
+// gets jumped by either P1 or P3, depending on which has a peg in it.
 type move struct {
-    // P2 is the peg in the middle that gets jumped by either
-    // P1 or P3, depending on which has a peg in it.
-	P1, P2, P3 uint
-}
-
-// all possible moves
-var pmoves = []move{
-	move{0, 1, 3},
-	move{0, 2, 5},
-	move{1, 3, 6},
-	move{1, 4, 8},
-	move{2, 4, 7},
-	move{2, 5, 9},
-	move{3, 4, 5},
-	move{3, 7, 12},
-	move{3, 6, 10},
-	move{4, 7, 11},
-	move{4, 8, 13},
-	move{5, 8, 12},
-	move{5, 9, 14},
-	move{6, 7, 8},
-	move{7, 8, 9},
-	move{10, 11, 12},
-	move{12, 13, 14},
-	move{13, 12, 11},
-}
-
-const maxUint = ^uint(0)
-
-// game represents the peg board in its current state.  We use a single
-// unsigned integer to represent the current state of the game.
-type game struct {
-	Board uint
-}
-
-// creates a new instance of a game ready to play
-func newGame() game {
-    // generate a board with pegs in ALL holes
-	g := game{maxUint}
-    // remove one random peg
-	n := uint(rand.Intn(15))
-	g = g.toggleBit(n)
-	return g
-}
-
-// get the value (1 or 0) of the specified bit
-func (g game) bitValue(hole uint) uint {
-	if (g.Board & (1 << hole)) > 0 {
-		return 1
+	P1, P2, P3 int
+}
+
+// holes returns the number of holes in a triangle of side n.
+func holes(n int) int {
+	return n * (n + 1) / 2
+}
+
+// hole returns the hole index for the peg at (row, col), both
+// zero-based, where row has row+1 holes numbered 0..row.
+func hole(row, col int) int {
+	return row*(row+1)/2 + col
+}
+
+// Moves generates every possible move on a triangle of side n by
+// walking each hole's three axis directions: horizontal, down-left,
+// and down-right. Each direction contributes a move when its
+// midpoint and destination both land inside the triangle.
+func Moves(n int) []move {
+	var moves []move
+	for row := 0; row < n; row++ {
+		for col := 0; col <= row; col++ {
+			p1 := hole(row, col)
+
+			// horizontal: same row, two holes to the right
+			if col+2 <= row {
+				moves = append(moves, move{p1, hole(row, col+1), hole(row, col+2)})
+			}
+			if row+2 < n {
+				// down-right: two rows down, one column right each row
+				moves = append(moves, move{p1, hole(row+1, col+1), hole(row+2, col+2)})
+				// down-left: two rows down, same column
+				moves = append(moves, move{p1, hole(row+1, col), hole(row+2, col)})
+			}
+		}
 	}
-	return 0
+	return moves
+}
+
+// Board represents a peg board's current state, one bit per hole. A
+// set bit means the hole has a peg in it.
+type Board struct {
+	bits *big.Int
+}
+
+// NewGame returns a board of side n with every hole full except the
+// given hole, which starts empty.
+func NewGame(n int, empty int) Board {
+	one := big.NewInt(1)
+	full := new(big.Int).Sub(new(big.Int).Lsh(one, uint(holes(n))), one)
+	full.SetBit(full, empty, 0)
+	return Board{full}
+}
+
+// creates a new instance of a game, of side n, ready to play
+func newGame(n int, r *rng.RNG) Board {
+	return NewGame(n, r.Intn(holes(n)))
 }
 
-// toggle the specified bit
-func (g game) toggleBit(n uint) game {
-	g.Board ^= (1 << n)
-	return g
+// get the value (1 or 0) of the specified hole
+func (b Board) bitValue(hole int) uint {
+	return uint(b.bits.Bit(hole))
+}
+
+// toggle the specified hole
+func (b Board) toggleBit(hole int) Board {
+	nb := new(big.Int).Set(b.bits)
+	nb.SetBit(nb, hole, 1-nb.Bit(hole))
+	return Board{nb}
 }
 
 // returns true if the specified move can be played
-func (g game) CanPlay(m move) bool {
-	if g.bitValue(m.P2) == 0 {
+func (b Board) CanPlay(m move) bool {
+	if b.bitValue(m.P2) == 0 {
 		return false
 	}
-	return g.bitValue(m.P1) != g.bitValue(m.P3)
+	return b.bitValue(m.P1) != b.bitValue(m.P3)
 }
 
-// returns the count of pegs remaining in the game
-func (g game) PegCnt() int {
+// returns the count of pegs remaining on a board of side n
+func (b Board) PegCnt(n int) int {
 	cnt := 0
-	for n := uint(0); n < 15; n++ {
-		if g.bitValue(n) == 1 {
+	for h := 0; h < holes(n); h++ {
+		if b.bitValue(h) == 1 {
 			cnt++
 		}
 	}
 	return cnt
 }
 
-// prints an ASCII art representation of the game
-func (g game) Print() {
-	fmt.Printf("    %d\n", g.bitValue(0))
-	fmt.Printf("   %d %d\n", g.bitValue(1), g.bitValue(2))
-	fmt.Printf("  %d %d %d\n", g.bitValue(3), g.bitValue(4), g.bitValue(5))
-	fmt.Printf(" %d %d %d %d\n", g.bitValue(6), g.bitValue(7), g.bitValue(8), g.bitValue(9))
-	fmt.Printf("%d %d %d %d %d\n", g.bitValue(10), g.bitValue(11), g.bitValue(12), g.bitValue(13), g.bitValue(14))
+// Fprint writes an ASCII art representation of a board of side n to w.
+func (b Board) Fprint(w io.Writer, n int) {
+	h := 0
+	for row := 0; row < n; row++ {
+		fmt.Fprint(w, strings.Repeat(" ", n-1-row))
+		for col := 0; col <= row; col++ {
+			if col > 0 {
+				fmt.Fprint(w, " ")
+			}
+			fmt.Fprintf(w, "%d", b.bitValue(h))
+			h++
+		}
+		fmt.Fprintln(w)
+	}
 }
 
 // plays the specified move
-func (g game) Play(m move) game {
-	g = g.toggleBit(m.P1)
-	g = g.toggleBit(m.P2)
-	g = g.toggleBit(m.P3)
-	return g
+func (b Board) Play(m move) Board {
+	b = b.toggleBit(m.P1)
+	b = b.toggleBit(m.P2)
+	b = b.toggleBit(m.P3)
+	return b
 }
 
-// recursive function that plays all possible paths of the specified game
-func play(g game, moves []uint, solvedCh chan []uint) {
+// play plays all possible paths of the specified game, single
+// threaded, closing solvedCh once the whole tree below b has been
+// explored. See search for the recursion itself.
+func play(b Board, n int, moves []move, path []Board, solvedCh chan []Board, perms [6][]int, dead *deadEnds) bool {
+	solved := search(context.Background(), b, n, moves, path, solvedCh, perms, dead)
+	if len(path) == 1 {
+		close(solvedCh)
+	}
+	return solved
+}
+
+// search recursively plays all possible paths of the specified game
+// and reports whether any solution was found at or below b. When dead
+// is non-nil, branches whose canonical form under perms are already
+// known to be dead are skipped, and branches that turn out to have no
+// solutions beneath them are recorded as dead; dead is safe to share
+// across concurrent callers. search stops early, without closing
+// solvedCh, once ctx is done, so multiple workers can share it and a
+// caller can cancel once it has what it needs.
+func search(ctx context.Context, b Board, n int, moves []move, path []Board, solvedCh chan []Board, perms [6][]int, dead *deadEnds) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	default:
+	}
+
+	var canon Board
+	if dead != nil {
+		canon = Canonicalize(b, perms)
+		if dead.Contains(canon) {
+			return false
+		}
+	}
+
+	solved := false
 	moved := false
-	for _, m := range pmoves {
-		if g.CanPlay(m) {
-			g2 := g.Play(m)
-			mvs := append([]uint(nil), moves...)
-			mvs = append(mvs, g2.Board)
-			play(g.Play(m), mvs, solvedCh)
+	for _, m := range moves {
+		if b.CanPlay(m) {
+			b2 := b.Play(m)
+			p := append([]Board(nil), path...)
+			p = append(p, b2)
+			if search(ctx, b2, n, moves, p, solvedCh, perms, dead) {
+				solved = true
+			}
 			moved = true
 		}
 	}
 
-	if !moved && g.PegCnt() == 1 {
-		solvedCh <- moves
+	if !moved && b.PegCnt(n) == 1 {
+		select {
+		case solvedCh <- path:
+			solved = true
+		case <-ctx.Done():
+		}
 	}
 
-	if len(moves) == 1 {
-		close(solvedCh)
+	if !solved && dead != nil && ctx.Err() == nil {
+		dead.Add(canon)
 	}
+
+	return solved
 }
 
 func main() {
     // parse command line
     var printCnt int
+    var n int
+    var dedup bool
+    var seed uint64
+    var format string
+    var workers int
     flag.IntVar(&printCnt, "p", 1, "number of solutions to print")
+    flag.IntVar(&n, "n", 5, "side length of the triangle board")
+    flag.BoolVar(&dedup, "dedup", false, "skip branches already proven unsolvable, up to board symmetry")
+    flag.Uint64Var(&seed, "seed", 0, "seed the RNG for a reproducible run (0 seeds from crypto/rand)")
+    flag.StringVar(&format, "format", "ascii", "solution output format: ascii, json, or ndjson")
+    flag.IntVar(&workers, "workers", runtime.NumCPU(), "number of parallel search workers")
     flag.Parse()
 
+    if n < 1 {
+        fmt.Fprintf(os.Stderr, "-n must be at least 1, got %d\n", n)
+        os.Exit(1)
+    }
+
+    if workers < 1 {
+        fmt.Fprintf(os.Stderr, "-workers must be at least 1, got %d\n", workers)
+        os.Exit(1)
+    }
+
+    var formatter Formatter
+    switch format {
+    case "ascii":
+        formatter = ASCIIFormatter{}
+    case "json":
+        formatter = JSONFormatter{Indent: true}
+    case "ndjson":
+        formatter = JSONFormatter{}
+    default:
+        fmt.Fprintf(os.Stderr, "unknown -format %q: must be ascii, json, or ndjson\n", format)
+        os.Exit(1)
+    }
+
     // seed random generator
-	rand.Seed(time.Now().UTC().UnixNano())
+    var r *rng.RNG
+    if seed == 0 {
+        r = rng.NewRNG()
+    } else {
+        r = rng.NewRNGFromSeed(seed)
+    }
 
-   // create a game board and start the solver in the background
-	g := newGame()
-	solutionsCh := make(chan []uint)
-	moves := []uint{g.Board}
-	go play(g, moves, solutionsCh)
+   // create a game board and start the worker pool searching it
+	b := newGame(n, r)
+	var perms [6][]int
+	var dead *deadEnds
+	if dedup {
+		perms = symmetries(n)
+		dead = newDeadEnds()
+	}
 
-    // loop to read solutions as the solver finds them
-	for i := 0; ; i++ {
-		solution := <-solutionsCh
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	solutionsCh := Solve(ctx, b, n, workers, perms, dead)
 
-		if solution == nil {
-            if printCnt < 0 {
-               printCnt = i
+    // loop to read solutions as the solver finds them, stopping early
+    // once we've printed as many as requested
+	printed := 0
+	for solution := range solutionsCh {
+        if printCnt < 0 || printed < printCnt {
+            if err := formatter.Format(os.Stdout, n, solution); err != nil {
+                fmt.Fprintf(os.Stderr, "formatting solution: %v\n", err)
             }
-			fmt.Printf("Printed %d of %d solutions.\n", printCnt, i+1)
-			return
-		}
-
-        if i < printCnt || printCnt < 0{
-		 for _, move := range solution {
-		    game{move}.Print()
-		    fmt.Println(" ")
-		 }
+            printed++
+        }
+        if printCnt >= 0 && printed >= printCnt {
+            cancel()
+            break
         }
 	}
+	fmt.Printf("Printed %d solutions.\n", printed)
 }
@@ -0,0 +1,57 @@
+package rng
+
+import "testing"
+
+// TestNewRNGFromSeedIsReproducible asserts that two generators seeded
+// with the same value produce the same sequence, and that different
+// seeds diverge - the property the -seed flag relies on.
+func TestNewRNGFromSeedIsReproducible(t *testing.T) {
+	a := NewRNGFromSeed(42)
+	b := NewRNGFromSeed(42)
+	for i := 0; i < 8; i++ {
+		av, bv := a.Uint64(), b.Uint64()
+		if av != bv {
+			t.Fatalf("draw %d: got %d and %d from two RNGs seeded alike", i, av, bv)
+		}
+	}
+
+	c := NewRNGFromSeed(43)
+	if c.Uint64() == NewRNGFromSeed(42).Uint64() {
+		t.Fatal("different seeds produced the same first draw")
+	}
+}
+
+// TestNewRNGFromSeedRegression pins seed 42's first few draws to known
+// values, so a change to the generator's state update or scrambler is
+// caught as a behavior change rather than silently reshuffling every
+// reproducible run built on this package.
+func TestNewRNGFromSeedRegression(t *testing.T) {
+	want := []uint64{
+		1546998764402558742,
+		6990951692964543102,
+		12544586762248559009,
+		17057574109182124193,
+		18295552978065317476,
+	}
+
+	r := NewRNGFromSeed(42)
+	for i, w := range want {
+		if got := r.Uint64(); got != w {
+			t.Fatalf("draw %d: got %d, want %d", i, got, w)
+		}
+	}
+}
+
+// TestIntnReproducesAcrossIdenticallySeededRNGs checks that Intn,
+// which both newGame and the -seed flag rely on, is just as
+// reproducible as the underlying Uint64 stream.
+func TestIntnReproducesAcrossIdenticallySeededRNGs(t *testing.T) {
+	a := NewRNGFromSeed(42)
+	b := NewRNGFromSeed(42)
+	for i := 0; i < 8; i++ {
+		av, bv := a.Intn(15), b.Intn(15)
+		if av != bv {
+			t.Fatalf("draw %d: got %d and %d from two RNGs seeded alike", i, av, bv)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Jump is one {from, over, to} move within a recorded Solution.
+type Jump struct {
+	From int `json:"from"`
+	Over int `json:"over"`
+	To   int `json:"to"`
+}
+
+// Solution is a structured, render-agnostic record of one solved
+// game: the side of the board, the hole that started empty, the
+// ordered jumps that were played, and the final board.
+type Solution struct {
+	N     int    `json:"n"`
+	Empty int    `json:"empty"`
+	Moves []Jump `json:"moves"`
+	Final Board  `json:"final"`
+}
+
+// newSolution recovers the ordered moves played along path, a
+// sequence of boards from the initial position (exactly one empty
+// hole) to a one-peg end state, by diffing each consecutive pair of
+// boards against moves, the full move table for a triangle of side n.
+func newSolution(n int, path []Board, moves []move) (Solution, error) {
+	s := Solution{N: n, Final: path[len(path)-1]}
+
+	for h := 0; h < holes(n); h++ {
+		if path[0].bitValue(h) == 0 {
+			s.Empty = h
+			break
+		}
+	}
+
+	for i := 1; i < len(path); i++ {
+		j, err := diff(path[i-1], path[i], moves)
+		if err != nil {
+			return Solution{}, err
+		}
+		s.Moves = append(s.Moves, j)
+	}
+	return s, nil
+}
+
+// diff returns the jump that explains the transition from before to
+// after: the move table entry whose three holes all flip, as a jump
+// would, oriented so From is the end that actually held the peg in
+// before (moves are undirected in the table, so either P1 or P3 may
+// be the one that was occupied).
+func diff(before, after Board, moves []move) (Jump, error) {
+	for _, m := range moves {
+		if before.bitValue(m.P1) != after.bitValue(m.P1) &&
+			before.bitValue(m.P2) != after.bitValue(m.P2) &&
+			before.bitValue(m.P3) != after.bitValue(m.P3) {
+			if before.bitValue(m.P1) == 1 {
+				return Jump{From: m.P1, Over: m.P2, To: m.P3}, nil
+			}
+			return Jump{From: m.P3, Over: m.P2, To: m.P1}, nil
+		}
+	}
+	return Jump{}, fmt.Errorf("no move explains the transition between the given boards")
+}
+
+// Replay plays s's moves against a fresh board of side s.N starting
+// at s.Empty, and reports whether it reaches exactly one peg and
+// matches s.Final.
+func Replay(s Solution) (bool, error) {
+	b := NewGame(s.N, s.Empty)
+	for _, j := range s.Moves {
+		m := move{j.From, j.Over, j.To}
+		if !b.CanPlay(m) {
+			return false, fmt.Errorf("move %+v cannot be played from the current board", j)
+		}
+		b = b.Play(m)
+	}
+	return b.PegCnt(s.N) == 1 && b.bits.Cmp(s.Final.bits) == 0, nil
+}
+
+// MarshalJSON encodes the board as a hex string of its bitboard, so
+// boards of any side length survive the round trip through JSON.
+func (b Board) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.bits.Text(16))
+}
+
+// UnmarshalJSON decodes a board previously encoded by MarshalJSON.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	bits, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		return fmt.Errorf("invalid board encoding %q", s)
+	}
+	b.bits = bits
+	return nil
+}
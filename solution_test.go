@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestReplayMatchesRecordedSolution(t *testing.T) {
+	n := 4
+	b := NewGame(n, 1)
+	moves := Moves(n)
+
+	solvedCh := make(chan []Board)
+	go play(b, n, moves, []Board{b}, solvedCh, [6][]int{}, nil)
+
+	path, ok := <-solvedCh
+	if !ok {
+		t.Fatal("expected at least one solution")
+	}
+
+	s, err := newSolution(n, path, moves)
+	if err != nil {
+		t.Fatalf("newSolution: %v", err)
+	}
+
+	replayed, err := Replay(s)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !replayed {
+		t.Fatal("replayed solution did not reach one peg matching the recorded final board")
+	}
+}
+
+// TestDiffOrientsJumpByWhichEndHeldThePeg checks that diff reports
+// From as the hole that actually held the peg, not just whichever
+// hole the move table happens to call P1 - moves are undirected in
+// the table, so a jump can be played from either end.
+func TestDiffOrientsJumpByWhichEndHeldThePeg(t *testing.T) {
+	n := 5
+	moves := Moves(n)
+
+	// move{0, 2, 5} is a real entry in Moves(5): the down-right line
+	// from hole 0 through hole 2 to hole 5. Play it "backwards" -
+	// peg at 5 jumping over 2 into 0 - and check diff reports that
+	// direction rather than the table's P1->P3 order.
+	before := NewGame(n, 0) // every hole full except 0
+	after := before.Play(move{0, 2, 5})
+
+	j, err := diff(before, after, moves)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if j.From != 5 || j.Over != 2 || j.To != 0 {
+		t.Fatalf("diff reported From:%d Over:%d To:%d, want From:5 Over:2 To:0", j.From, j.Over, j.To)
+	}
+}
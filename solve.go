@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// jobDepth is how many plies deep Solve seeds the job queue. Each job
+// is an independent subtree a worker can search without coordinating
+// with the others.
+const jobDepth = 4
+
+// job is one unit of search work: a path from the initial board down
+// to the board a worker should continue searching from.
+type job struct {
+	path []Board
+}
+
+// jobsAtDepth returns one job per board reachable from the board at
+// the end of path after exactly depth further plies, or fewer at a
+// branch with no legal moves left. Seeding Solve's job queue this way
+// gives its workers independent subtrees to search in parallel.
+func jobsAtDepth(n int, moves []move, path []Board, depth int) []job {
+	b := path[len(path)-1]
+	if depth == 0 {
+		return []job{{path: path}}
+	}
+
+	var jobs []job
+	moved := false
+	for _, m := range moves {
+		if b.CanPlay(m) {
+			p := append([]Board(nil), path...)
+			p = append(p, b.Play(m))
+			jobs = append(jobs, jobsAtDepth(n, moves, p, depth-1)...)
+			moved = true
+		}
+	}
+	if !moved {
+		jobs = append(jobs, job{path: path})
+	}
+	return jobs
+}
+
+// Solve searches the game tree rooted at g in parallel across
+// workers goroutines, each pulling independent subtrees from a
+// buffered job queue seeded jobDepth plies deep. workers below 1 is
+// treated as 1. perms and dead, if dead is non-nil, prune symmetric
+// dead branches exactly as the sequential search does. The returned
+// channel is closed once every worker has finished; callers can stop
+// the search early - for example after printing as many solutions as
+// requested - by canceling ctx.
+func Solve(ctx context.Context, g Board, n int, workers int, perms [6][]int, dead *deadEnds) <-chan []Board {
+	if workers < 1 {
+		workers = 1
+	}
+
+	moves := Moves(n)
+	jobs := jobsAtDepth(n, moves, []Board{g}, jobDepth)
+
+	jobCh := make(chan job, len(jobs))
+	for _, j := range jobs {
+		jobCh <- j
+	}
+	close(jobCh)
+
+	out := make(chan []Board)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobCh {
+				search(ctx, j.path[len(j.path)-1], n, moves, j.path, out, perms, dead)
+			}
+		}()
+	}
+
+	// sentinel goroutine: close out only once every worker is done,
+	// so a caller ranging over it, as main does, sees a clean end of
+	// the stream.
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// drain reads every solution off ch, returning the count once ch is
+// closed or failing the test if that takes too long.
+func drain(t *testing.T, ch <-chan []Board) int {
+	t.Helper()
+	cnt := 0
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return cnt
+			}
+			cnt++
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for Solve's channel to close")
+		}
+	}
+}
+
+// TestSolveMatchesSequentialSearch checks that the parallel worker
+// pool finds the same number of solutions as the single-threaded
+// play, with and without the symmetry dead-end table.
+func TestSolveMatchesSequentialSearch(t *testing.T) {
+	n := 4
+	b := NewGame(n, 1)
+
+	want := solve(n, b, false)
+
+	got := drain(t, Solve(context.Background(), b, n, 4, [6][]int{}, nil))
+	if got != want {
+		t.Fatalf("Solve without dedup found %d solutions, sequential search found %d", got, want)
+	}
+
+	perms := symmetries(n)
+	dead := newDeadEnds()
+	gotDedup := drain(t, Solve(context.Background(), b, n, 4, perms, dead))
+	if gotDedup != want {
+		t.Fatalf("Solve with dedup found %d solutions, sequential search found %d", gotDedup, want)
+	}
+}
+
+// TestSolveStopsEarlyWhenCanceled exercises the combination main uses:
+// a shared dead-end table across workers, canceling the context once
+// enough solutions have been printed. The channel must still close
+// promptly afterward instead of leaving workers running forever.
+func TestSolveStopsEarlyWhenCanceled(t *testing.T) {
+	n := 5
+	b := NewGame(n, 1)
+	perms := symmetries(n)
+	dead := newDeadEnds()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := Solve(ctx, b, n, 4, perms, dead)
+
+	select {
+	case _, ok := <-ch:
+		if !ok {
+			t.Fatal("expected at least one solution before canceling")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first solution")
+	}
+
+	cancel()
+
+	// drain whatever's left; the point is that this returns at all.
+	drain(t, ch)
+}